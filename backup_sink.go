@@ -0,0 +1,225 @@
+package roller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupSink is the destination a rotated backup is handed off to, and the
+// place retention (MaxSize, FileMaxCount, FileMaxAge, ReservedSize) looks for
+// existing backups. The default, used when Options.Sink is unset, is
+// LocalFSSink, which reproduces this package's original local-rename-and-
+// compress behavior; ExecSink pipes backups through an external command
+// instead, e.g. to ship them to object storage.
+type BackupSink interface {
+	// Put hands a freshly rotated backup's content to the sink. name is the
+	// backup's logical filename, as produced by Options.RotateName; meta
+	// describes its rotation time, size, and checksum.
+	Put(ctx context.Context, name string, r io.Reader, meta Metadata) error
+	// List returns the Metadata for every backup the sink currently holds,
+	// used to apply retention. Sinks that can't enumerate their own backups
+	// (ExecSink) return an error, and retention is skipped for that pass.
+	List(ctx context.Context) ([]Metadata, error)
+	// Delete removes the named backup, as reported by List.
+	Delete(ctx context.Context, name string) error
+	// Open opens the named backup, as reported by List, for reading.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// LocalFSSink stores backups as files in Dir, the same directory as the
+// active log file, optionally compressed with Compressor. It is the default
+// sink, wired up from Options so its behavior matches this package's
+// pre-BackupSink behavior exactly.
+type LocalFSSink struct {
+	Dir              string
+	Prefix           string
+	Ext              string
+	BackupTimeFormat string
+	Compressor       Compressor
+	CompressLevel    int
+}
+
+func (s *LocalFSSink) Put(ctx context.Context, name string, r io.Reader, meta Metadata) error {
+	if s.Compressor == nil {
+		f, err := os.Create(filepath.Join(s.Dir, name))
+		if err != nil {
+			return fmt.Errorf("local sink: %w", err)
+		}
+		defer f.Close()
+		if _, err := io.Copy(f, r); err != nil {
+			return fmt.Errorf("local sink: %w", err)
+		}
+		return f.Close()
+	}
+
+	dst := filepath.Join(s.Dir, name+s.Compressor.Suffix())
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("local sink: %w", err)
+	}
+	defer f.Close()
+	if err := s.Compressor.Compress(f, r, meta, s.CompressLevel); err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("local sink: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *LocalFSSink) List(ctx context.Context) ([]Metadata, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("local sink: %w", err)
+	}
+
+	var metas []Metadata
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if c, ok := compressorForName(name); ok {
+			meta, err := s.peekMetadata(name, c)
+			if err != nil {
+				continue
+			}
+			metas = append(metas, meta)
+			continue
+		}
+		if t, ok := s.parseBackupTime(name); ok {
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			metas = append(metas, Metadata{Name: name, RotatedAt: t, UncompressedSize: info.Size()})
+		}
+	}
+	return metas, nil
+}
+
+// peekMetadata reads just the embedded Metadata header of a compressed
+// backup, without decompressing its body.
+func (s *LocalFSSink) peekMetadata(name string, c Compressor) (Metadata, error) {
+	f, err := os.Open(filepath.Join(s.Dir, name))
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+	rc, meta, err := c.Decompress(f)
+	if err != nil {
+		return Metadata{}, err
+	}
+	rc.Close()
+	return meta, nil
+}
+
+// parseBackupTime extracts the rotation time from an uncompressed backup's
+// filename, in the same way LumberjackRoller.timeFromName does.
+func (s *LocalFSSink) parseBackupTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, s.Prefix) || !strings.HasSuffix(name, s.Ext) {
+		return time.Time{}, false
+	}
+	ts := name[len(s.Prefix) : len(name)-len(s.Ext)]
+	t, err := time.Parse(s.BackupTimeFormat, ts)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func (s *LocalFSSink) Delete(ctx context.Context, name string) error {
+	path, err := s.resolvePath(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (s *LocalFSSink) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.resolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := compressorForName(filepath.Base(path)); ok {
+		rc, _, err := c.Decompress(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return rc, nil
+	}
+	return f, nil
+}
+
+// resolvePath maps a logical backup name, as reported by List, back to its
+// path on disk, trying every known compressor suffix since List strips it.
+func (s *LocalFSSink) resolvePath(name string) (string, error) {
+	direct := filepath.Join(s.Dir, name)
+	if _, err := os.Stat(direct); err == nil {
+		return direct, nil
+	}
+	for suffix := range compressorsBySuffix {
+		p := direct + suffix
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("local sink: backup %q not found", name)
+}
+
+func compressorForName(name string) (Compressor, bool) {
+	for suffix, c := range compressorsBySuffix {
+		if strings.HasSuffix(name, suffix) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// ExecSink pipes each rotated backup's content through an external command,
+// e.g. "gzip | aws s3 cp - s3://bucket/key", analogous to how logrotate
+// composes postrotate scripts. Command is run via "sh -c" with the backup's
+// content as its stdin; any occurrence of $ROLLER_BACKUP_NAME in Command is
+// replaced with the backup's name first.
+//
+// List, Delete, and Open return an error: once a backup's content has been
+// piped to an external command, this package has no way to enumerate or
+// retrieve it again, so retention for ExecSink-backed backups is whatever
+// the command ships to provides on its own.
+type ExecSink struct {
+	Command string
+}
+
+func (s *ExecSink) Put(ctx context.Context, name string, r io.Reader, meta Metadata) error {
+	command := strings.ReplaceAll(s.Command, "$ROLLER_BACKUP_NAME", name)
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = r
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("exec sink: %w: %s", err, out)
+	}
+	return nil
+}
+
+var errExecSinkUnsupported = fmt.Errorf("exec sink: backups shipped via command cannot be listed, deleted, or reopened")
+
+func (s *ExecSink) List(ctx context.Context) ([]Metadata, error) {
+	return nil, errExecSinkUnsupported
+}
+
+func (s *ExecSink) Delete(ctx context.Context, name string) error {
+	return errExecSinkUnsupported
+}
+
+func (s *ExecSink) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return nil, errExecSinkUnsupported
+}
@@ -0,0 +1,124 @@
+package roller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalFSSinkRoundTrip(t *testing.T) {
+	for _, compressor := range []Compressor{nil, compressors["gzip"], compressors["zstd"], compressors["lz4"]} {
+		name := "uncompressed"
+		if compressor != nil {
+			name = compressor.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := &LocalFSSink{
+				Dir:              dir,
+				Prefix:           "test-",
+				Ext:              ".log",
+				BackupTimeFormat: "2006-01-02T15-04-05",
+				Compressor:       compressor,
+			}
+
+			ctx := context.Background()
+			rotatedAt := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+			meta := Metadata{
+				Name:             "test-2026-01-02T15-04-05.log",
+				RotatedAt:        rotatedAt,
+				UncompressedSize: 5,
+				SHA256:           "abc123",
+			}
+			content := []byte("hello")
+
+			if err := s.Put(ctx, meta.Name, bytes.NewReader(content), meta); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			metas, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(metas) != 1 {
+				t.Fatalf("got %d backups, want 1: %+v", len(metas), metas)
+			}
+			if metas[0].Name != meta.Name {
+				t.Fatalf("List name = %q, want %q", metas[0].Name, meta.Name)
+			}
+			if compressor != nil && metas[0] != meta {
+				// Only compressed backups embed the full Metadata; List derives
+				// uncompressed ones from the filename and file size alone.
+				t.Fatalf("List metadata = %+v, want %+v", metas[0], meta)
+			}
+
+			rc, err := s.Open(ctx, metas[0].Name)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			got, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Fatalf("content = %q, want %q", got, content)
+			}
+
+			if err := s.Delete(ctx, metas[0].Name); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			metas, err = s.List(ctx)
+			if err != nil {
+				t.Fatalf("List after delete: %v", err)
+			}
+			if len(metas) != 0 {
+				t.Fatalf("got %d backups after delete, want 0", len(metas))
+			}
+		})
+	}
+}
+
+func TestLocalFSSinkOpenUnknownName(t *testing.T) {
+	dir := t.TempDir()
+	s := &LocalFSSink{Dir: dir, Prefix: "test-", Ext: ".log", BackupTimeFormat: "2006-01-02T15-04-05"}
+	if _, err := s.Open(context.Background(), "nope.log"); err == nil {
+		t.Fatal("expected an error opening a backup that was never Put")
+	}
+}
+
+func TestExecSinkPutRunsCommand(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	s := &ExecSink{Command: "cat > " + out}
+
+	if err := s.Put(context.Background(), "test.log", bytes.NewReader([]byte("hello")), Metadata{}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read command output: %v", err)
+	}
+	if !bytes.Equal(got, []byte("hello")) {
+		t.Fatalf("command received %q, want %q", got, "hello")
+	}
+}
+
+func TestExecSinkListDeleteOpenUnsupported(t *testing.T) {
+	s := &ExecSink{Command: "cat"}
+	ctx := context.Background()
+	if _, err := s.List(ctx); err == nil {
+		t.Fatal("expected List to report it's unsupported")
+	}
+	if err := s.Delete(ctx, "test.log"); err == nil {
+		t.Fatal("expected Delete to report it's unsupported")
+	}
+	if _, err := s.Open(ctx, "test.log"); err == nil {
+		t.Fatal("expected Open to report it's unsupported")
+	}
+}
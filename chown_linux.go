@@ -0,0 +1,21 @@
+//go:build linux
+// +build linux
+
+package roller
+
+import (
+	"os"
+	"syscall"
+)
+
+// chown changes the ownership of the named file to match info, which was
+// obtained by stating the file this one replaces. It is a no-op on every
+// platform but linux, where log files are commonly owned by a dedicated
+// service user.
+func chown(name string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	return os.Chown(name, int(stat.Uid), int(stat.Gid))
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package roller
+
+import "os"
+
+// chown is a no-op on every platform but linux.
+func chown(_ string, _ os.FileInfo) error {
+	return nil
+}
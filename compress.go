@@ -0,0 +1,170 @@
+package roller
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Compressor compresses and decompresses a single backup file, embedding a
+// Metadata header so the backup can be inspected without a full
+// decompression pass. Implementations must be safe to reuse across
+// concurrent Compress/Decompress calls since they hold no per-file state.
+type Compressor interface {
+	// Name identifies the codec, e.g. "gzip", for use with the Compression
+	// option.
+	Name() string
+	// Suffix is the filename suffix appended to compressed backups, e.g.
+	// ".gz".
+	Suffix() string
+	// Compress reads src to completion, writing the compressed result and
+	// an embedded Metadata header to dst. level is codec-specific; 0 means
+	// "use the codec's default".
+	Compress(dst io.Writer, src io.Reader, meta Metadata, level int) error
+	// Decompress reads a stream previously written by Compress, returning a
+	// ReadCloser over the decompressed content and the embedded Metadata.
+	Decompress(src io.Reader) (io.ReadCloser, Metadata, error)
+}
+
+// gzipCompressor implements Compressor using compress/gzip, storing the
+// Metadata header as JSON in the gzip comment field.
+type gzipCompressor struct{}
+
+func (gzipCompressor) Name() string   { return "gzip" }
+func (gzipCompressor) Suffix() string { return ".gz" }
+
+func (gzipCompressor) Compress(dst io.Writer, src io.Reader, meta Metadata, level int) error {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	gw, err := gzip.NewWriterLevel(dst, level)
+	if err != nil {
+		return err
+	}
+	header, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	gw.Name = meta.Name
+	gw.Comment = string(header)
+	gw.ModTime = meta.RotatedAt
+	if _, err := io.Copy(gw, src); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func (gzipCompressor) Decompress(src io.Reader) (io.ReadCloser, Metadata, error) {
+	gr, err := gzip.NewReader(src)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	var meta Metadata
+	_ = json.Unmarshal([]byte(gr.Comment), &meta)
+	return gr, meta, nil
+}
+
+// framedCompressor implements the shared metadata framing used by codecs
+// without a native comment/header field: a big-endian uint32 length followed
+// by the JSON-encoded Metadata, followed by the compressed stream.
+type framedCompressor struct {
+	name      string
+	suffix    string
+	newWriter func(w io.Writer, level int) (io.WriteCloser, error)
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+func (c framedCompressor) Name() string   { return c.name }
+func (c framedCompressor) Suffix() string { return c.suffix }
+
+func (c framedCompressor) Compress(dst io.Writer, src io.Reader, meta Metadata, level int) error {
+	header, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(dst, binary.BigEndian, uint32(len(header))); err != nil {
+		return err
+	}
+	if _, err := dst.Write(header); err != nil {
+		return err
+	}
+	w, err := c.newWriter(dst, level)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (c framedCompressor) Decompress(src io.Reader) (io.ReadCloser, Metadata, error) {
+	var headerLen uint32
+	if err := binary.Read(src, binary.BigEndian, &headerLen); err != nil {
+		return nil, Metadata{}, fmt.Errorf("can't read %s metadata header: %w", c.name, err)
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, Metadata{}, fmt.Errorf("can't read %s metadata header: %w", c.name, err)
+	}
+	var meta Metadata
+	_ = json.Unmarshal(header, &meta)
+	rc, err := c.newReader(src)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return rc, meta, nil
+}
+
+var zstdCodec = framedCompressor{
+	name:   "zstd",
+	suffix: ".zst",
+	newWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		if level == 0 {
+			return zstd.NewWriter(w)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	},
+	newReader: func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	},
+}
+
+var lz4Codec = framedCompressor{
+	name:   "lz4",
+	suffix: ".lz4",
+	newWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		zw := lz4.NewWriter(w)
+		if level > 0 {
+			if err := zw.Apply(lz4.CompressionLevelOption(lz4.CompressionLevel(level))); err != nil {
+				return nil, err
+			}
+		}
+		return zw, nil
+	},
+	newReader: func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(lz4.NewReader(r)), nil
+	},
+}
+
+// compressors is the registry consulted by the Compression option.
+var compressors = map[string]Compressor{
+	"gzip": gzipCompressor{},
+	"zstd": zstdCodec,
+	"lz4":  lz4Codec,
+}
+
+var compressorsBySuffix = map[string]Compressor{
+	".gz":  compressors["gzip"],
+	".zst": compressors["zstd"],
+	".lz4": compressors["lz4"],
+}
@@ -0,0 +1,61 @@
+package roller
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestCompressorRoundTrip verifies that every registered Compressor can
+// decompress exactly what it compressed, recovering both the original bytes
+// and the embedded Metadata.
+func TestCompressorRoundTrip(t *testing.T) {
+	meta := Metadata{
+		Name:             "test-2026-01-02T15-04-05.log",
+		RotatedAt:        time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		UncompressedSize: 13,
+		SHA256:           "deadbeef",
+	}
+	content := []byte("hello, world!")
+
+	for name, c := range compressors {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.Compress(&buf, bytes.NewReader(content), meta, 0); err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+
+			rc, gotMeta, err := c.Decompress(&buf)
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			defer rc.Close()
+
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("read decompressed content: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Fatalf("decompressed content = %q, want %q", got, content)
+			}
+			if gotMeta != meta {
+				t.Fatalf("metadata = %+v, want %+v", gotMeta, meta)
+			}
+		})
+	}
+}
+
+// TestCompressorsBySuffixMatchesName verifies the suffix registry stays in
+// sync with each codec's own Name/Suffix, so compressorForName and the
+// Compression option agree on which codec a name implies.
+func TestCompressorsBySuffixMatchesName(t *testing.T) {
+	for suffix, c := range compressorsBySuffix {
+		if c.Suffix() != suffix {
+			t.Fatalf("compressorsBySuffix[%q] has Suffix() = %q", suffix, c.Suffix())
+		}
+		if compressors[c.Name()].Name() != c.Name() {
+			t.Fatalf("compressorsBySuffix[%q]'s codec isn't compressors[%q]", suffix, c.Name())
+		}
+	}
+}
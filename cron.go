@@ -0,0 +1,138 @@
+package roller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a small five-field cron expression — minute, hour,
+// day-of-month, month, day-of-week — supporting "*", comma lists, ranges
+// ("a-b"), and steps ("*/n" or "a-b/n"). It exists so TimeRotateStrategy can
+// compute rotation instants without an external dependency.
+type cronSchedule struct {
+	minute, hour, dom, month, dow fieldSet
+
+	// domStar and dowStar record whether the day-of-month and day-of-week
+	// fields were literally "*", so matches can apply cron's usual rule that
+	// a day matches if dom OR dow matches whenever both fields are
+	// restricted, rather than requiring both (see matches).
+	domStar, dowStar bool
+}
+
+// fieldSet is the set of values a cron field matches.
+type fieldSet map[int]bool
+
+// parseCron parses a standard five-field cron expression, e.g. "0 0 * * *"
+// for daily or "0 * * * *" for hourly.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q: want 5 fields, got %d", expr, len(fields))
+	}
+
+	minute, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("cron minute field: %w", err)
+	}
+	hour, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("cron hour field: %w", err)
+	}
+	dom, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("cron day-of-month field: %w", err)
+	}
+	month, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("cron month field: %w", err)
+	}
+	dow, err := parseCronField(fields[4], 0, 6)
+	if err != nil {
+		return nil, fmt.Errorf("cron day-of-week field: %w", err)
+	}
+
+	return &cronSchedule{
+		minute: minute, hour: hour, dom: dom, month: month, dow: dow,
+		domStar: fields[2] == "*", dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a single cron field, a comma-separated list of
+// "*", "*/step", "n", "a-b", or "a-b/step" items, within [min, max].
+func parseCronField(field string, min, max int) (fieldSet, error) {
+	set := fieldSet{}
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			rangePart = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", item)
+			}
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			parts := strings.SplitN(rangePart, "-", 2)
+			var err error
+			lo, err = strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range start in %q", item)
+			}
+			hi, err = strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range end in %q", item)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", item)
+			}
+			lo, hi = n, n
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value %q out of range [%d, %d]", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// next returns the first instant strictly after t that matches the
+// schedule, checked minute by minute.
+func (c *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Bound the search so a malformed schedule (e.g. Feb 30) can't spin
+	// forever; five years of minutes is far more than any real gap.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.matches(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return limit
+}
+
+// matches reports whether t falls on the schedule. Per standard cron rules,
+// day-of-month and day-of-week are ANDed with the rest of the fields, except
+// against each other: if both are restricted (neither is "*"), a day matches
+// when either one does, rather than requiring both.
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	domMatch, dowMatch := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	if !c.domStar && !c.dowStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
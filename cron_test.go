@@ -0,0 +1,87 @@
+package roller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsWrongFieldCount(t *testing.T) {
+	if _, err := parseCron("0 0 * *"); err == nil {
+		t.Fatal("expected an error for a four-field expression")
+	}
+}
+
+func TestParseCronRejectsOutOfRangeValue(t *testing.T) {
+	if _, err := parseCron("60 0 * * *"); err == nil {
+		t.Fatal("expected an error for minute 60")
+	}
+}
+
+func TestCronNextDaily(t *testing.T) {
+	c, err := parseCron("0 0 * * *")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	from := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	want := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if got := c.next(from); !got.Equal(want) {
+		t.Fatalf("next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+func TestCronNextHourly(t *testing.T) {
+	c, err := parseCron("0 * * * *")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	from := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	want := time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+	if got := c.next(from); !got.Equal(want) {
+		t.Fatalf("next(%v) = %v, want %v", from, got, want)
+	}
+}
+
+// TestCronDomDowOR verifies standard cron semantics: when both day-of-month
+// and day-of-week are restricted, a day matches if EITHER matches, not both.
+func TestCronDomDowOR(t *testing.T) {
+	c, err := parseCron("0 0 1,15 * 1")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+
+	// Monday Jan 5 2026 is neither the 1st nor the 15th, but is a Monday.
+	monday := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	if !c.matches(monday) {
+		t.Fatalf("expected %v (a Monday) to match via the day-of-week OR", monday)
+	}
+
+	// Jan 15 2026 is a Thursday, not a Monday, but is the 15th.
+	fifteenth := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	if !c.matches(fifteenth) {
+		t.Fatalf("expected %v (the 15th) to match via the day-of-month OR", fifteenth)
+	}
+
+	// Jan 6 2026 is a Tuesday and neither the 1st nor the 15th: no match.
+	other := time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC)
+	if c.matches(other) {
+		t.Fatalf("did not expect %v to match", other)
+	}
+}
+
+// TestCronDomOnlyIsAND verifies that restricting only one of day-of-month or
+// day-of-week still behaves as a plain AND against the other (unrestricted)
+// field, since an unrestricted "*" field matches every day anyway.
+func TestCronDomOnlyIsAND(t *testing.T) {
+	c, err := parseCron("0 0 15 * *")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	fifteenth := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	if !c.matches(fifteenth) {
+		t.Fatalf("expected the 15th to match")
+	}
+	sixteenth := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	if c.matches(sixteenth) {
+		t.Fatalf("did not expect the 16th to match")
+	}
+}
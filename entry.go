@@ -0,0 +1,48 @@
+package roller
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// Field is a single structured key/value pair serialized by WriteEntry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// entryBufPool holds the scratch buffers WriteEntry serializes an entry
+// into, so repeated calls don't each allocate one.
+var entryBufPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// WriteEntry formats level, ts, msg, and fields into a single buffer and
+// issues one Write, rather than the several small writes a naive structured
+// logger would make per field. level and ts are taken as pre-formatted
+// bytes so callers that already avoid allocating a time string (e.g. via
+// time.Time.AppendFormat) don't lose that benefit here.
+//
+// Pair this with BufferedWrite to also coalesce entries from many
+// goroutines into fewer underlying writes.
+func (r *LumberjackRoller) WriteEntry(level, ts, msg []byte, fields ...Field) (int, error) {
+	buf := entryBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer entryBufPool.Put(buf)
+
+	buf.Write(level)
+	buf.WriteByte(' ')
+	buf.Write(ts)
+	buf.WriteByte(' ')
+	buf.Write(msg)
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		fmt.Fprint(buf, f.Value)
+	}
+	buf.WriteByte('\n')
+
+	return r.Write(buf.Bytes())
+}
@@ -0,0 +1,61 @@
+package roller
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newBenchRoller(b *testing.B, opts ...Option) *LumberjackRoller {
+	b.Helper()
+	dir := b.TempDir()
+	base := append([]Option{Filename(filepath.Join(dir, "bench.log")), Size(1 << 30)}, opts...)
+	roller, err := NewLumberjackRollerFromOptions(NewOptions(base...))
+	if err != nil {
+		b.Fatalf("NewLumberjackRollerFromOptions: %v", err)
+	}
+	r := roller.(*LumberjackRoller)
+	b.Cleanup(func() { r.Close() })
+	return r
+}
+
+func BenchmarkWriteEntry(b *testing.B) {
+	r := newBenchRoller(b)
+	level, ts, msg := []byte("INFO"), []byte("2026-01-01T00:00:00Z"), []byte("request handled")
+	fields := []Field{{Key: "status", Value: 200}, {Key: "latency_ms", Value: 12}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.WriteEntry(level, ts, msg, fields...); err != nil {
+			b.Fatalf("WriteEntry: %v", err)
+		}
+	}
+}
+
+// BenchmarkWrite_Unbuffered and BenchmarkWrite_Buffered compare per-Write
+// lock and syscall overhead with BufferedWrite off versus on.
+func BenchmarkWrite_Unbuffered(b *testing.B) {
+	r := newBenchRoller(b)
+	line := []byte("INFO 2026-01-01T00:00:00Z request handled status=200\n")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Write(line); err != nil {
+			b.Fatalf("Write: %v", err)
+		}
+	}
+}
+
+func BenchmarkWrite_Buffered(b *testing.B) {
+	r := newBenchRoller(b, BufferedWrite(64*1024, 0))
+	line := []byte("INFO 2026-01-01T00:00:00Z request handled status=200\n")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Write(line); err != nil {
+			b.Fatalf("Write: %v", err)
+		}
+	}
+}
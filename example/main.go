@@ -5,7 +5,7 @@ import (
 	"log"
 	"time"
 
-	"github.com/haormj/roller/v2"
+	"github.com/haormj/roller"
 )
 
 func main() {
@@ -13,13 +13,10 @@ func main() {
 		roller.Filename("./test.log"),
 		roller.Size(1024),
 		roller.Duration(30*time.Second),
-		roller.LifecycleGlob("./test/test_*.log"),
-		roller.LifecycleCount(10),
-		roller.LifecycleDuration(time.Minute),
-		roller.LifecycleSize(10*1024),
-		roller.RotateName(func(s string) string {
-			return fmt.Sprintf("./test/test_%d.log", time.Now().UnixMilli())
-		}),
+		roller.FileMaxCount(10),
+		roller.FileMaxAge(time.Minute),
+		roller.MaxSize(10*1024),
+		roller.Compression("zstd"),
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -0,0 +1,19 @@
+//go:build linux
+// +build linux
+
+package roller
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the inode backing info, used to detect that a peer process
+// has rotated the active file out from under us.
+func fileID(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return stat.Ino, true
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package roller
+
+import "os"
+
+// fileID has no portable equivalent outside linux; MultiProcess falls back
+// to trusting its own in-process state on these platforms.
+func fileID(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
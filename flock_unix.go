@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package roller
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// flock takes an advisory lock on f, blocking until it is available.
+// exclusive selects LOCK_EX over LOCK_SH.
+func flock(f *os.File, exclusive bool) error {
+	how := unix.LOCK_SH
+	if exclusive {
+		how = unix.LOCK_EX
+	}
+	return unix.Flock(int(f.Fd()), how)
+}
+
+// funlock releases a lock taken with flock.
+func funlock(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
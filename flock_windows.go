@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package roller
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// flock takes an advisory lock on f, blocking until it is available.
+// exclusive selects an exclusive lock over a shared one.
+func flock(f *os.File, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	ol := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, ol)
+}
+
+// funlock releases a lock taken with flock.
+func funlock(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
@@ -1,7 +1,10 @@
 package roller
 
 import (
-	"compress/gzip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -24,17 +27,25 @@ func (c constError) Error() string {
 // size allowed in a single file.
 const ErrWriteTooLong = constError("write exceeds max file length")
 
+// NewRoller returns a new Roller configured with the given options. It is the
+// preferred entry point; NewLumberjackRoller is kept as an alias for callers
+// migrating from the pre-consolidation API.
+func NewRoller(opts ...Option) (Roller, error) {
+	options := NewOptions(opts...)
+	return NewLumberjackRollerFromOptions(options)
+}
+
 // NewLumberjackRoller returns a new Roller.
 //
-// If the file exists and is less than maxSize bytes, lumberjack will open and
-// append to that file. If the file exists and its size is >= maxSize bytes, the
-// file is renamed by putting the current time in a timestamp in the name
+// If the file exists and is less than FileMaxSize bytes, it will be opened
+// and appended to. If the file exists and its size is >= FileMaxSize bytes,
+// the file is renamed by putting the current time as a timestamp in the name
 // immediately before the file's extension (or the end of the filename if
-// there's no extension). A new log file is then created using original
+// there's no extension). A new log file is then created using the original
 // filename.
 //
-// An error is returned if a file cannot be opened or created, or if maxsize is
-// 0 or less.
+// An error is returned if a file cannot be opened or created, or if
+// FileMaxSize is 0 or less under SizeRotateStrategy.
 func NewLumberjackRoller(opts ...Option) (Roller, error) {
 	options := NewOptions(opts...)
 	return NewLumberjackRollerFromOptions(options)
@@ -47,40 +58,119 @@ func NewLumberjackRollerFromOptions(options Options) (Roller, error) {
 	if options.FileName == "" {
 		return nil, errors.New("filename cannot be empty")
 	}
+	if options.BackupTimeFormat == "" {
+		options.BackupTimeFormat = backupTimeFormat
+	}
+	if options.BackupTimeLocation == nil {
+		options.BackupTimeLocation = time.Local
+	}
+	if options.RotateName == nil {
+		options.RotateName = func(name string) string {
+			return backupName(name, options.BackupTimeFormat, options.BackupTimeLocation)
+		}
+	}
 	r := &LumberjackRoller{
 		opts: options,
 	}
+	if options.Sink != nil {
+		r.sink = options.Sink
+	} else {
+		compressor, _ := r.compressor()
+		prefix, ext := r.prefixAndExt()
+		r.sink = &LocalFSSink{
+			Dir:              r.dir(),
+			Prefix:           prefix,
+			Ext:              ext,
+			BackupTimeFormat: options.BackupTimeFormat,
+			Compressor:       compressor,
+			CompressLevel:    options.CompressLevel,
+		}
+	}
+	if options.RotateStrategy == TimeRotateStrategy {
+		if options.Schedule.Cron != "" {
+			cron, err := parseCron(options.Schedule.Cron)
+			if err != nil {
+				return nil, err
+			}
+			r.cron = cron
+		} else if options.Schedule.Duration <= 0 {
+			return nil, errors.New("schedule must set Cron or a positive Duration")
+		}
+	}
+	if options.BufferSize > 0 {
+		r.buf = new(bytes.Buffer)
+		if options.FlushInterval > 0 {
+			r.startFlusher()
+		}
+	}
 	if err := r.openExistingOrNew(0); err != nil {
 		return nil, fmt.Errorf("can't open file: %w", err)
 	}
+	if options.RotateStrategy == TimeRotateStrategy {
+		if err := r.catchUpSchedule(); err != nil {
+			return nil, err
+		}
+		r.startSchedule()
+	}
 	return r, nil
 }
 
-// Roller wraps a file, intercepting its writes to control its size, rolling the
-// old file over to a different name before writing to a new one.
-//
-// Whenever a write would cause the current log file exceed maxSize bytes, the
-// current file is closed, renamed, and a new log file created with the original
-// name. Thus, the filename you give Roller is always the "current" log file.
+// LumberjackRoller wraps a file, intercepting its writes to control its size
+// or cadence, rolling the old file over to a different name before writing
+// to a new one. It is the sole Roller implementation in this package; the
+// RotateStrategy and Duration options select between size-triggered,
+// every-write, and time-triggered rotation.
 //
-// Backups use the log file name given to Roller, in the form
+// Backups use the log file name given to the Roller, in the form
 // `name-timestamp.ext` where name is the filename without the extension,
-// timestamp is the time at which the log was rotated formatted with the
-// time.Time format of `2006-01-02T15-04-05.000` and the extension is the
-// original extension. For example, if your Roller.Filename is
-// `/var/log/foo/server.log`, a backup created at 6:30pm on Nov 11 2016 would
-// use the filename `/var/log/foo/server-2016-11-04T18-30-00.000.log`
+// timestamp is the time at which the log was rotated formatted with
+// BackupTimeFormat, and the extension is the original extension. For
+// example, if FileName is `/var/log/foo/server.log`, a backup created at
+// 6:30pm on Nov 4 2016 would use the filename
+// `/var/log/foo/server-2016-11-04T18-30-00.000.log`.
 //
 // # Cleaning Up Old Log Files
 //
-// Whenever a new logfile gets created, old log files may be deleted. The most
-// recent files according to the encoded timestamp will be retained, up to a
-// number equal to MaxBackups (or all of them if MaxBackups is 0). Any files
-// with an encoded timestamp older than MaxAge days are deleted, regardless of
-// MaxBackups. Note that the time encoded in the timestamp is the rotation
-// time, which may differ from the last time that file was written to.
+// Whenever a new logfile gets created, old log files may be deleted. The
+// most recent files according to the encoded timestamp will be retained, up
+// to a number equal to FileMaxCount (or all of them if FileMaxCount is 0).
+// Any files with an encoded timestamp older than FileMaxAge are deleted,
+// regardless of FileMaxCount. Any files beyond the cumulative MaxSize budget
+// are deleted, oldest first. Note that the time encoded in the timestamp is
+// the rotation time, which may differ from the last time that file was
+// written to. Finally, if ReservedSize is set, the oldest surviving backups
+// are deleted until the filesystem holding the log directory has at least
+// that many free bytes.
+//
+// If MaxSize, FileMaxCount, FileMaxAge, and ReservedSize are all 0, no old
+// log files will be deleted.
+//
+// # Backup Storage
+//
+// Where a rotated backup ends up is delegated to a BackupSink: by default a
+// LocalFSSink that writes into the active file's directory, compressing with
+// the codec selected via Compression if any, but Sink can replace this with
+// something that ships backups elsewhere, such as an ExecSink piping them
+// through an external command. Retention above is applied against whatever
+// the sink's List reports, so it works the same way regardless of where
+// backups actually live.
 //
-// If MaxBackups and MaxAge are both 0, no old log files will be deleted.
+// # Multiple Processes
+//
+// By default only one process may safely write to FileName; a second writer
+// racing a rotation can corrupt the rename. Setting MultiProcess has writers
+// take an advisory lock on a sidecar `.lock` file around appends and
+// rotations, and record the active file's identity in a `.rotate-state`
+// sidecar so peers notice a rotation performed out from under them and
+// reopen instead of racing to rotate again.
+//
+// # Buffered Writes
+//
+// By default every Write lands in the active file immediately. Setting
+// BufferedWrite coalesces writes into an in-memory buffer instead, flushed
+// once it reaches the configured size, on the configured interval, or
+// immediately before a rotation, trading a small window of unflushed data
+// for less per-Write lock and syscall overhead under concurrent logging.
 type LumberjackRoller struct {
 	opts Options
 
@@ -88,22 +178,50 @@ type LumberjackRoller struct {
 	file *os.File
 	mu   sync.Mutex
 
+	createTime time.Time
+
 	millCh    chan bool
 	startMill sync.Once
+
+	// sink is where rotated backups are written, listed, and deleted. Set
+	// from Options.Sink, or a LocalFSSink built from Options if unset.
+	sink BackupSink
+
+	// MultiProcess coordination state; see multiprocess.go.
+	lockFile    *os.File
+	activeInode uint64
+	haveInode   bool
+	rotateEpoch int64
+	lastEpoch   int64
+
+	// TimeRotateStrategy scheduling state.
+	cron         *cronSchedule
+	scheduleOnce sync.Once
+	stopSchedule chan struct{}
+
+	// buf holds writes not yet landed in file, under BufferedWrite. nil
+	// means unbuffered: every Write lands in file immediately.
+	buf       *bytes.Buffer
+	flushOnce sync.Once
+	stopFlush chan struct{}
 }
 
 var (
 	// currentTime exists so it can be mocked out by tests.
 	currentTime = time.Now
 
-	// os_Stat exists so it can be mocked out by tests.
+	// osStat exists so it can be mocked out by tests.
 	osStat = os.Stat
+
+	// diskFreeFunc exists so it can be mocked out by tests.
+	diskFreeFunc = diskFree
 )
 
-// Write implements io.Writer.  If a write would cause the log file to be larger
-// than MaxSize, the file is closed, renamed to include a timestamp of the
-// current time, and a new log file is created using the original log file name.
-// If the length of the write is greater than MaxSize, an error is returned.
+// Write implements io.Writer. Depending on RotateStrategy, a write that would
+// cause the log file to be larger than FileMaxSize, or every write under
+// DirectRotateStrategy, closes the file, renames it to include a timestamp of
+// the current time, and creates a new log file using the original name. A
+// write longer than FileMaxSize returns ErrWriteTooLong instead of rotating.
 func (r *LumberjackRoller) Write(p []byte) (n int, err error) {
 	writeLen := int64(len(p))
 	if r.opts.FileMaxSize > 0 && writeLen > r.opts.FileMaxSize {
@@ -115,29 +233,189 @@ func (r *LumberjackRoller) Write(p []byte) (n int, err error) {
 	defer r.mu.Unlock()
 	r.mu.Lock()
 
-	switch r.opts.RotateStrategy {
-	case SizeRotateStrategy:
-		if r.size+writeLen > r.opts.FileMaxSize {
-			if err := r.rotate(); err != nil {
-				return 0, err
+	if r.opts.MultiProcess {
+		if err := r.syncWithPeers(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.opts.ReservedSize > 0 && r.breachesReserve(writeLen) {
+		r.mill()
+	}
+
+	if r.needsRotate(writeLen) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	write := func() error {
+		if r.buf != nil {
+			bn, _ := r.buf.Write(p)
+			n = bn
+			r.size += int64(n)
+			if r.buf.Len() >= r.opts.BufferSize {
+				if ferr := r.flushLocked(); ferr != nil {
+					err = ferr
+					return ferr
+				}
 			}
+			return nil
 		}
 		n, err = r.file.Write(p)
 		r.size += int64(n)
-	case DirectRotateStrategy:
-		n, err = r.file.Write(p)
-		r.size += int64(n)
-		if err := r.rotate(); err != nil {
-			return 0, err
+		return err
+	}
+	if r.opts.MultiProcess {
+		if lockErr := r.withLock(false, write); lockErr != nil && err == nil {
+			err = lockErr
 		}
+	} else {
+		write()
+	}
+
+	if r.opts.RotateStrategy == DirectRotateStrategy && err == nil {
+		err = r.rotate()
 	}
 	return n, err
 }
 
+// needsRotate reports whether the pending write should trigger a rotation
+// before it lands, under SizeRotateStrategy or the Duration age check. Under
+// DirectRotateStrategy every write rotates after landing, handled in Write.
+func (r *LumberjackRoller) needsRotate(writeLen int64) bool {
+	if r.opts.RotateStrategy == SizeRotateStrategy && r.size+writeLen > r.opts.FileMaxSize {
+		return true
+	}
+	if r.opts.Duration > 0 && !r.createTime.IsZero() && currentTime().Sub(r.createTime) > r.opts.Duration {
+		return true
+	}
+	return false
+}
+
+// breachesReserve reports whether landing a write of writeLen would push the
+// free space on the log directory's filesystem under ReservedSize.
+func (r *LumberjackRoller) breachesReserve(writeLen int64) bool {
+	available, err := diskFreeFunc(r.dir())
+	if err != nil {
+		// if we can't statfs the log directory, don't let that block writes.
+		return false
+	}
+	return int64(available)-writeLen < r.opts.ReservedSize
+}
+
+// nextRotation returns the next instant after t at which TimeRotateStrategy
+// should rotate.
+func (r *LumberjackRoller) nextRotation(t time.Time) time.Time {
+	if r.cron != nil {
+		return r.cron.next(t)
+	}
+	return t.Add(r.opts.Schedule.Duration)
+}
+
+// catchUpSchedule compares the active file's modification time against the
+// current time, both bucketed by BackupTimeFormat, and rotates immediately
+// if they differ and the next scheduled rotation after the modification
+// time is already due — catching up a rotation that was due while the
+// process was down.
+func (r *LumberjackRoller) catchUpSchedule() error {
+	r.mu.Lock()
+	createTime := r.createTime
+	r.mu.Unlock()
+
+	if createTime.IsZero() {
+		return nil
+	}
+	now := currentTime()
+	if createTime.Format(r.opts.BackupTimeFormat) == now.Format(r.opts.BackupTimeFormat) {
+		return nil
+	}
+	if r.nextRotation(createTime).After(now) {
+		return nil
+	}
+	return r.Rotate()
+}
+
+// startSchedule starts the goroutine that sleeps until the next scheduled
+// rotation and invokes Rotate, under TimeRotateStrategy.
+func (r *LumberjackRoller) startSchedule() {
+	r.scheduleOnce.Do(func() {
+		r.stopSchedule = make(chan struct{})
+		go r.scheduleRun()
+	})
+}
+
+func (r *LumberjackRoller) scheduleRun() {
+	for {
+		next := r.nextRotation(currentTime())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			// what am I going to do, log this?
+			_ = r.Rotate()
+		case <-r.stopSchedule:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// startFlusher starts the goroutine that periodically flushes the write
+// buffer, under BufferedWrite with a positive flush interval.
+func (r *LumberjackRoller) startFlusher() {
+	r.flushOnce.Do(func() {
+		r.stopFlush = make(chan struct{})
+		go r.flushRun()
+	})
+}
+
+func (r *LumberjackRoller) flushRun() {
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.mu.Lock()
+			// what am I going to do, log this?
+			_ = r.flushLocked()
+			r.mu.Unlock()
+		case <-r.stopFlush:
+			return
+		}
+	}
+}
+
+// flushLocked writes any buffered, not-yet-written bytes to the active file
+// and fsyncs it. Callers must hold r.mu.
+func (r *LumberjackRoller) flushLocked() error {
+	if r.buf == nil || r.buf.Len() == 0 {
+		return nil
+	}
+	if _, err := r.file.Write(r.buf.Bytes()); err != nil {
+		return fmt.Errorf("can't flush buffered log writes: %w", err)
+	}
+	r.buf.Reset()
+	return r.file.Sync()
+}
+
 // Close implements io.Closer, and closes the current logfile.
 func (r *LumberjackRoller) Close() error {
+	if r.stopSchedule != nil {
+		close(r.stopSchedule)
+	}
+	if r.stopFlush != nil {
+		close(r.stopFlush)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if err := r.flushLocked(); err != nil {
+		return err
+	}
+	if r.lockFile != nil {
+		r.lockFile.Close()
+		r.lockFile = nil
+	}
 	return r.close()
 }
 
@@ -151,33 +429,51 @@ func (r *LumberjackRoller) close() error {
 	return err
 }
 
-// Rotate causes Logger to close the existing log file and immediately create a
-// new one.  This is a helper function for applications that want to initiate
-// rotations outside of the normal rotation rules, such as in response to
-// SIGHUP.  After rotating, this initiates compression and removal of old log
-// files according to the configuration.
+// Rotate causes the Roller to close the existing log file and immediately
+// create a new one. This is a helper function for applications that want to
+// initiate rotations outside of the normal rotation rules, such as in
+// response to SIGHUP. After rotating, this initiates shipping to the
+// configured BackupSink and removal of old log files according to the
+// configuration.
 func (r *LumberjackRoller) Rotate() error {
 	defer r.mu.Unlock()
 	r.mu.Lock()
 	return r.rotate()
 }
 
-// rotate closes the current file, moves it aside with a timestamp in the name,
-// (if it exists), opens a new file with the original filename, and then runs
-// post-rotation processing and removar.
+// rotate closes the current file, moves it aside with a timestamp in the
+// name (if it exists), opens a new file with the original filename, and then
+// runs post-rotation processing and removal. Under MultiProcess it holds the
+// sidecar lock exclusively so a peer can't observe or perform a rotation at
+// the same time.
 func (r *LumberjackRoller) rotate() error {
+	if r.opts.MultiProcess {
+		return r.withLock(true, r.rotateLocked)
+	}
+	return r.rotateLocked()
+}
+
+func (r *LumberjackRoller) rotateLocked() error {
+	if err := r.flushLocked(); err != nil {
+		return err
+	}
 	if err := r.close(); err != nil {
 		return err
 	}
 	if err := r.openNew(); err != nil {
 		return err
 	}
+	if r.opts.MultiProcess {
+		if err := r.writeRotateState(); err != nil {
+			return err
+		}
+	}
 	r.mill()
 	return nil
 }
 
-// openNew opens a new log file for writing, moving any old log file out of the
-// way.  This methods assumes the file has already been closed.
+// openNew opens a new log file for writing, moving any old log file out of
+// the way. This method assumes the file has already been closed.
 func (r *LumberjackRoller) openNew() error {
 	err := os.MkdirAll(r.dir(), 0755)
 	if err != nil {
@@ -186,51 +482,62 @@ func (r *LumberjackRoller) openNew() error {
 
 	name := r.newFilename()
 	mode := os.FileMode(0600)
-	info, err := osStat(name)
+	oldInfo, err := osStat(name)
 	if err == nil {
 		// Copy the mode off the old logfile.
-		mode = info.Mode()
+		mode = oldInfo.Mode()
 		// move the existing file
-		newname := r.backupName(name, r.opts.BackupTimeLocation)
+		newname := r.opts.RotateName(name)
 		if err := os.Rename(name, newname); err != nil {
 			return fmt.Errorf("can't rename log file: %w", err)
 		}
-
-		// this is a no-op anywhere but linux
-		if err := chown(name, info); err != nil {
-			return err
-		}
 	}
 
-	// we use truncate here because this should only get called when we've moved
-	// the file ourselves. if someone else creates the file in the meantime,
-	// just wipe out the contents.
-	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	// we use truncate here because this should only get called when we've
+	// moved the file ourselves. if someone else creates the file in the
+	// meantime, just wipe out the contents. O_APPEND keeps this fd's writes
+	// landing at the true end of file under MultiProcess, where a peer may
+	// append through its own descriptor between two of this process's
+	// writes; without it, this fd's own last-known offset would stomp on
+	// whatever the peer just appended.
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, mode)
 	if err != nil {
 		return fmt.Errorf("can't open new logfile: %w", err)
 	}
+	if oldInfo != nil {
+		// this is a no-op anywhere but linux; carry the old logfile's
+		// ownership onto the new one now that it exists.
+		if err := chown(name, oldInfo); err != nil {
+			f.Close()
+			return err
+		}
+	}
 	r.file = f
 	r.size = 0
+	r.createTime = currentTime()
+	if r.opts.MultiProcess {
+		r.recordActiveFile()
+	}
 	return nil
 }
 
-// backupName creates a new filename from the given name, inserting a timestamp
-// between the filename and the extension, using the local time if requested
-// (otherwise UTC).
-func (r *LumberjackRoller) backupName(name string, l *time.Location) string {
+// backupName creates a new filename from the given name, inserting a
+// timestamp between the filename and the extension, using the given
+// location.
+func backupName(name, format string, l *time.Location) string {
 	dir := filepath.Dir(name)
 	filename := filepath.Base(name)
 	ext := filepath.Ext(filename)
 	prefix := filename[:len(filename)-len(ext)]
 	t := currentTime().In(l)
 
-	timestamp := t.Format(r.opts.BackupTimeFormat)
+	timestamp := t.Format(format)
 	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, timestamp, ext))
 }
 
 // openExistingOrNew opens the logfile if it exists and if the current write
-// would not put it over MaxSize.  If there is no such file or the write would
-// put it over the MaxSize, a new file is created.
+// would not put it over FileMaxSize. If there is no such file or the write
+// would put it over FileMaxSize, a new file is created.
 func (r *LumberjackRoller) openExistingOrNew(writeLen int64) error {
 	r.mill()
 
@@ -244,14 +551,18 @@ func (r *LumberjackRoller) openExistingOrNew(writeLen int64) error {
 	}
 
 	switch r.opts.RotateStrategy {
-	case SizeRotateStrategy:
-		if info.Size()+writeLen >= r.opts.FileMaxSize {
-			return r.rotate()
-		}
 	case DirectRotateStrategy:
 		if info.Size() > 0 {
 			return r.rotate()
 		}
+	case TimeRotateStrategy:
+		// Rotation here is on a schedule, not a function of this write;
+		// just append, and let catchUpSchedule decide whether a scheduled
+		// rotation was missed while the process was down.
+	default:
+		if info.Size()+writeLen >= r.opts.FileMaxSize {
+			return r.rotate()
+		}
 	}
 
 	file, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0644)
@@ -262,57 +573,60 @@ func (r *LumberjackRoller) openExistingOrNew(writeLen int64) error {
 	}
 	r.file = file
 	r.size = info.Size()
+	r.createTime = info.ModTime()
+	if r.opts.MultiProcess {
+		r.recordActiveFile()
+	}
 	return nil
 }
 
-// newFilename generates the name of the logfile from the current time.
+// newFilename returns the name of the active logfile.
 func (r *LumberjackRoller) newFilename() string {
 	if r.opts.FileName != "" {
 		return r.opts.FileName
 	}
-	name := filepath.Base(os.Args[0]) + "-lumberjack.log"
+	name := filepath.Base(os.Args[0]) + "-roller.log"
 	return filepath.Join(os.TempDir(), name)
 }
 
-// millRunOnce performs compression and removal of stale log files.
-// Log files are compressed if enabled via configuration and old log
-// files are removed, keeping at most r.MaxBackups files, as long as
-// none of them are older than MaxAge.
+// millRunOnce ships freshly rotated log files to the configured BackupSink
+// and, via the sink, removes stale backups so that at most FileMaxCount
+// remain, none are older than FileMaxAge, and the cumulative size of all
+// backups stays under MaxSize and above ReservedSize free space.
 func (r *LumberjackRoller) millRunOnce() error {
-	if r.opts.MaxSize == 0 && r.opts.FileMaxCount == 0 && r.opts.FileMaxAge == 0 && !r.opts.Compress {
+	ctx := context.Background()
+	needsShip := r.needsShipping()
+
+	if needsShip {
+		if err := r.shipPendingBackups(ctx); err != nil {
+			return err
+		}
+	}
+
+	if r.opts.MaxSize == 0 && r.opts.FileMaxCount == 0 && r.opts.FileMaxAge == 0 && r.opts.ReservedSize == 0 {
 		return nil
 	}
 
-	files, err := r.oldLogFiles()
+	files, err := r.sink.List(ctx)
 	if err != nil {
-		return err
+		// retention isn't meaningful against a sink that can't enumerate its
+		// own backups (e.g. ExecSink); nothing more to do this pass.
+		return nil
 	}
+	sort.Sort(byRotatedAt(files))
 
-	var compress, remove []logInfo
+	var remove []Metadata
 
-	if r.opts.MaxSize > 0 {
-		var remaining []logInfo
-		var total int64
-		for _, f := range files {
-			total += f.Size()
-			if total > r.opts.MaxSize {
-				remove = append(remove, f)
-			} else {
-				remaining = append(remaining, f)
-			}
-		}
-		files = remaining
-	}
 	if r.opts.FileMaxCount > 0 && r.opts.FileMaxCount < len(files) {
-		preserved := make(map[string]bool)
-		var remaining []logInfo
-		for _, f := range files {
-			// Only count the uncompressed log file or the
-			// compressed log file, not both.
-			fn := strings.TrimSuffix(f.Name(), r.opts.CompressSuffix)
-			preserved[fn] = true
+		remove = append(remove, files[r.opts.FileMaxCount:]...)
+		files = files[:r.opts.FileMaxCount]
+	}
+	if r.opts.FileMaxAge > 0 {
+		cutoff := currentTime().Add(-1 * r.opts.FileMaxAge)
 
-			if len(preserved) > r.opts.FileMaxCount {
+		var remaining []Metadata
+		for _, f := range files {
+			if f.RotatedAt.Before(cutoff) {
 				remove = append(remove, f)
 			} else {
 				remaining = append(remaining, f)
@@ -320,12 +634,12 @@ func (r *LumberjackRoller) millRunOnce() error {
 		}
 		files = remaining
 	}
-	if r.opts.FileMaxAge > 0 {
-		cutoff := currentTime().Add(-1 * time.Duration(r.opts.FileMaxAge))
-
-		var remaining []logInfo
+	if r.opts.MaxSize > 0 {
+		var remaining []Metadata
+		var total int64
 		for _, f := range files {
-			if f.timestamp.Before(cutoff) {
+			total += f.UncompressedSize
+			if total > r.opts.MaxSize {
 				remove = append(remove, f)
 			} else {
 				remaining = append(remaining, f)
@@ -334,33 +648,109 @@ func (r *LumberjackRoller) millRunOnce() error {
 		files = remaining
 	}
 
-	if r.opts.Compress {
-		for _, f := range files {
-			if !strings.HasSuffix(f.Name(), r.opts.CompressSuffix) {
-				compress = append(compress, f)
+	if r.opts.ReservedSize > 0 {
+		available, statErr := diskFreeFunc(r.dir())
+		if statErr != nil {
+			if err == nil {
+				err = statErr
+			}
+		} else {
+			// files is sorted newest first; trim from the tail, the oldest
+			// surviving backup, until the reserve is met or none are left.
+			// freed tracks bytes from backups already staged for removal
+			// this pass, since the actual deletion below hasn't run yet and
+			// so wouldn't otherwise be reflected in a repeated statfs.
+			var freed int64
+			for len(files) > 0 && int64(available)+freed < r.opts.ReservedSize {
+				oldest := files[len(files)-1]
+				freed += oldest.UncompressedSize
+				remove = append(remove, oldest)
+				files = files[:len(files)-1]
 			}
 		}
 	}
 
 	for _, f := range remove {
-		errRemove := os.Remove(filepath.Join(r.dir(), f.Name()))
+		errRemove := r.sink.Delete(ctx, f.Name)
 		if err == nil && errRemove != nil {
 			err = errRemove
 		}
 	}
-	for _, f := range compress {
-		fn := filepath.Join(r.dir(), f.Name())
-		errCompress := r.compressLogFile(fn, fn+r.opts.CompressSuffix)
-		if err == nil && errCompress != nil {
-			err = errCompress
+
+	return err
+}
+
+// needsShipping reports whether a freshly rotated, still-local backup file
+// needs to be handed to the sink before it's considered done: always true
+// for any sink other than the default LocalFSSink, since shipping is the
+// whole point of a custom sink, and also true for LocalFSSink when a
+// Compressor is configured, since that's what applies the compression.
+func (r *LumberjackRoller) needsShipping() bool {
+	if _, ok := r.sink.(*LocalFSSink); !ok {
+		return true
+	}
+	_, hasCompressor := r.compressor()
+	return hasCompressor
+}
+
+// shipPendingBackups finds backup files in the log directory that the mill
+// hasn't yet handed to the sink (i.e. carry none of this package's
+// compressor suffixes) and ships each one, removing the local raw copy once
+// the sink confirms it.
+func (r *LumberjackRoller) shipPendingBackups(ctx context.Context) error {
+	entries, err := ioutil.ReadDir(r.dir())
+	if err != nil {
+		return fmt.Errorf("can't read log file directory: %w", err)
+	}
+	prefix, ext := r.prefixAndExt()
+
+	var err2 error
+	for _, f := range entries {
+		if f.IsDir() {
+			continue
+		}
+		if _, ok := compressorForName(f.Name()); ok {
+			continue
+		}
+		t, perr := r.timeFromName(f.Name(), prefix, ext)
+		if perr != nil {
+			continue
+		}
+		if errShip := r.shipLogFile(ctx, f.Name(), t, f.Size()); err2 == nil && errShip != nil {
+			err2 = errShip
 		}
 	}
+	return err2
+}
 
-	return err
+// shipLogFile hands a single local, not-yet-shipped backup file to the sink,
+// removing the local copy once the sink confirms it.
+func (r *LumberjackRoller) shipLogFile(ctx context.Context, name string, rotatedAt time.Time, size int64) error {
+	path := filepath.Join(r.dir(), name)
+
+	sum, err := sha256File(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum log file: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	meta := Metadata{Name: name, RotatedAt: rotatedAt, UncompressedSize: size, SHA256: sum}
+	if err := r.sink.Put(ctx, name, f, meta); err != nil {
+		return fmt.Errorf("failed to ship log file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
 }
 
-// millRun runs in a goroutine to manage post-rotation compression and removal
-// of old log files.
+// millRun runs in a goroutine to manage post-rotation shipping and removal
+// of old log files without blocking Write.
 func (r *LumberjackRoller) millRun() {
 	for range r.millCh {
 		// what am I going to do, log this?
@@ -368,7 +758,7 @@ func (r *LumberjackRoller) millRun() {
 	}
 }
 
-// mill performs post-rotation compression and removal of stale log files,
+// mill performs post-rotation shipping and removal of stale log files,
 // starting the mill goroutine if necessary.
 func (r *LumberjackRoller) mill() {
 	r.startMill.Do(func() {
@@ -381,41 +771,9 @@ func (r *LumberjackRoller) mill() {
 	}
 }
 
-// oldLogFiles returns the list of backup log files stored in the same
-// directory as the current log file, sorted by ModTime
-func (r *LumberjackRoller) oldLogFiles() ([]logInfo, error) {
-	files, err := ioutil.ReadDir(r.dir())
-	if err != nil {
-		return nil, fmt.Errorf("can't read log file directory: %w", err)
-	}
-	logFiles := []logInfo{}
-
-	prefix, ext := r.prefixAndExt()
-
-	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-		if t, err := r.timeFromName(f.Name(), prefix, ext); err == nil {
-			logFiles = append(logFiles, logInfo{t, f})
-			continue
-		}
-		if t, err := r.timeFromName(f.Name(), prefix, ext+r.opts.CompressSuffix); err == nil {
-			logFiles = append(logFiles, logInfo{t, f})
-			continue
-		}
-		// error parsing means that the suffix at the end was not generated
-		// by lumberjack, and therefore it's not a backup file.
-	}
-
-	sort.Sort(byFormatTime(logFiles))
-
-	return logFiles, nil
-}
-
-// timeFromName extracts the formatted time from the filename by stripping off
-// the filename's prefix and extension. This prevents someone's filename from
-// confusing time.parse.
+// timeFromName extracts the formatted time from the filename by stripping
+// off the filename's prefix and extension. This prevents someone's filename
+// from confusing time.Parse.
 func (r *LumberjackRoller) timeFromName(filename, prefix, ext string) (time.Time, error) {
 	if !strings.HasPrefix(filename, prefix) {
 		return time.Time{}, errors.New("mismatched prefix")
@@ -432,8 +790,8 @@ func (r *LumberjackRoller) dir() string {
 	return filepath.Dir(r.newFilename())
 }
 
-// prefixAndExt returns the filename part and extension part from the Logger's
-// filename.
+// prefixAndExt returns the filename part and extension part from the
+// Roller's filename.
 func (r *LumberjackRoller) prefixAndExt() (prefix, ext string) {
 	filename := filepath.Base(r.newFilename())
 	ext = filepath.Ext(filename)
@@ -441,79 +799,78 @@ func (r *LumberjackRoller) prefixAndExt() (prefix, ext string) {
 	return prefix, ext
 }
 
-// compressLogFile compresses the given log file, removing the
-// uncompressed log file if successfur.
-func (r *LumberjackRoller) compressLogFile(src, dst string) (err error) {
-	f, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+// compressor resolves the Compressor configured via Compression, falling
+// back to gzip when the legacy Compress boolean is set.
+func (r *LumberjackRoller) compressor() (Compressor, bool) {
+	name := r.opts.Compression
+	if name == "" && r.opts.Compress {
+		name = "gzip"
 	}
-	defer f.Close()
-
-	fi, err := osStat(src)
-	if err != nil {
-		return fmt.Errorf("failed to stat log file: %w", err)
+	if name == "" {
+		return nil, false
 	}
+	c, ok := compressors[name]
+	return c, ok
+}
 
-	if err := chown(dst, fi); err != nil {
-		return fmt.Errorf("failed to chown compressed log file: %w", err)
-	}
+// Backups lists every backup currently held by the configured BackupSink.
+func (r *LumberjackRoller) Backups(ctx context.Context) ([]Metadata, error) {
+	return r.sink.List(ctx)
+}
 
-	// If this file already exists, we presume it was created by
-	// a previous attempt to compress the log file.
-	gzf, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+// OpenBackup opens a backup by name, as reported by Backups, transparently
+// decompressing it if it was written with a Compressor. It returns an error
+// if name doesn't match any backup Backups currently reports, rather than
+// risk handing back content under the wrong Metadata.
+func (r *LumberjackRoller) OpenBackup(name string) (io.ReadCloser, Metadata, error) {
+	ctx := context.Background()
+	metas, err := r.sink.List(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to open compressed log file: %w", err)
-	}
-	defer gzf.Close()
-
-	gz := gzip.NewWriter(gzf)
-
-	defer func() {
-		if err != nil {
-			os.Remove(dst)
-			err = fmt.Errorf("failed to compress log file: %w", err)
+		return nil, Metadata{}, fmt.Errorf("can't list backups: %w", err)
+	}
+	var meta Metadata
+	var found bool
+	for _, m := range metas {
+		if m.Name == name {
+			meta, found = m, true
+			break
 		}
-	}()
-
-	if _, err := io.Copy(gz, f); err != nil {
-		return err
 	}
-	if err := gz.Close(); err != nil {
-		return err
+	if !found {
+		return nil, Metadata{}, fmt.Errorf("no backup named %q", name)
 	}
-	if err := gzf.Close(); err != nil {
-		return err
+	rc, err := r.sink.Open(ctx, name)
+	if err != nil {
+		return nil, Metadata{}, err
 	}
+	return rc, meta, nil
+}
 
-	if err := f.Close(); err != nil {
-		return err
-	}
-	if err := os.Remove(src); err != nil {
-		return err
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
 	}
+	defer f.Close()
 
-	return nil
-}
-
-// logInfo is a convenience struct to return the filename and its embedded
-// timestamp.
-type logInfo struct {
-	timestamp time.Time
-	os.FileInfo
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-// byFormatTime sorts by newest time formatted in the name.
-type byFormatTime []logInfo
+// byRotatedAt sorts Metadata newest first.
+type byRotatedAt []Metadata
 
-func (b byFormatTime) Less(i, j int) bool {
-	return b[i].timestamp.After(b[j].timestamp)
+func (b byRotatedAt) Less(i, j int) bool {
+	return b[i].RotatedAt.After(b[j].RotatedAt)
 }
 
-func (b byFormatTime) Swap(i, j int) {
+func (b byRotatedAt) Swap(i, j int) {
 	b[i], b[j] = b[j], b[i]
 }
 
-func (b byFormatTime) Len() int {
+func (b byRotatedAt) Len() int {
 	return len(b)
 }
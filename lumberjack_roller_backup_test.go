@@ -0,0 +1,76 @@
+package roller
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestOpenBackupRoundTripWithCompression exercises the full Backups/
+// OpenBackup path through a real LumberjackRoller: a compressed backup's
+// on-disk dirent name (suffixed, e.g. ".zst") differs from the logical name
+// Backups reports, and OpenBackup must accept the latter, not the former.
+func TestOpenBackupRoundTripWithCompression(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+
+	options := NewOptions(Filename(filename), Size(1<<20), Compression("gzip"))
+	inst, err := NewLumberjackRollerFromOptions(options)
+	if err != nil {
+		t.Fatalf("NewLumberjackRollerFromOptions: %v", err)
+	}
+	r := inst.(*LumberjackRoller)
+	defer r.Close()
+
+	if _, err := r.Write([]byte("hello world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// Rotate leaves compression to the mill goroutine; wait until the only
+	// backup Backups reports is the compressed one, carrying its checksum.
+	var backups []Metadata
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		backups, err = r.Backups(context.Background())
+		if err != nil {
+			t.Fatalf("Backups: %v", err)
+		}
+		if (len(backups) == 1 && backups[0].SHA256 != "") || time.Now().After(deadline) {
+			break
+		}
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1: %+v", len(backups), backups)
+	}
+	meta := backups[0]
+	if meta.SHA256 == "" {
+		t.Fatalf("backup %q was never compressed: %+v", meta.Name, meta)
+	}
+
+	rc, gotMeta, err := r.OpenBackup(meta.Name)
+	if err != nil {
+		t.Fatalf("OpenBackup(%q): %v", meta.Name, err)
+	}
+	defer rc.Close()
+	if gotMeta != meta {
+		t.Fatalf("OpenBackup metadata = %+v, want %+v", gotMeta, meta)
+	}
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(got) != "hello world" {
+		t.Fatalf("backup content = %q, want %q", got, "hello world")
+	}
+
+	// The on-disk dirent name carries the compressor's suffix and differs
+	// from the logical name Backups reports; OpenBackup must reject it
+	// rather than silently pairing its content with a zero-value Metadata.
+	if _, _, err := r.OpenBackup(meta.Name + ".gz"); err == nil {
+		t.Fatalf("expected OpenBackup to reject the on-disk dirent name %q", meta.Name+".gz")
+	}
+}
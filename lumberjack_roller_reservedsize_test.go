@@ -0,0 +1,100 @@
+package roller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeDiskFree reports free space as a budget minus however many bytes of
+// backups are still sitting in dir, so removing a backup during a mill pass
+// is visible as more free space on the next call, the same way a real statfs
+// would behave without needing the test to consume real disk.
+func fakeDiskFree(budget int64, dir string) func(string) (uint64, error) {
+	return func(string) (uint64, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return 0, err
+		}
+		var used int64
+		for _, e := range entries {
+			if e.IsDir() || e.Name() == "test.log" {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			used += info.Size()
+		}
+		return uint64(budget - used), nil
+	}
+}
+
+func TestReservedSizeRemovesOldestBackupsUntilReserveMet(t *testing.T) {
+	origDiskFree := diskFreeFunc
+	defer func() { diskFreeFunc = origDiskFree }()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	if err := os.WriteFile(filename, []byte("active"), 0644); err != nil {
+		t.Fatalf("seed active file: %v", err)
+	}
+
+	options := NewOptions(
+		Filename(filename),
+		Size(1<<20),
+		ReservedSize(150),
+	)
+
+	// Built directly rather than via NewLumberjackRollerFromOptions, which
+	// would kick off a background mill pass (since ReservedSize>0 bypasses
+	// its early-return) racing with diskFreeFunc below; millRunOnce only
+	// needs opts and sink, both set up the same way the constructor does.
+	r := &LumberjackRoller{opts: options}
+	compressor, _ := r.compressor()
+	prefix, ext := r.prefixAndExt()
+	r.sink = &LocalFSSink{
+		Dir:              r.dir(),
+		Prefix:           prefix,
+		Ext:              ext,
+		BackupTimeFormat: options.BackupTimeFormat,
+		Compressor:       compressor,
+		CompressLevel:    options.CompressLevel,
+	}
+
+	// Seed three backups directly, oldest first, as if produced by earlier
+	// rotations.
+	oldest := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotatedAt := oldest
+	for _, body := range []string{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"} {
+		name := filename[:len(filename)-len(".log")] + "-" + rotatedAt.Format(options.BackupTimeFormat) + ".log"
+		if err := os.WriteFile(name, []byte(body), 0644); err != nil {
+			t.Fatalf("seed backup: %v", err)
+		}
+		rotatedAt = rotatedAt.Add(time.Minute)
+	}
+
+	// Budget big enough that two 10-byte backups satisfy the 150-byte
+	// reserve, but not all three.
+	diskFreeFunc = fakeDiskFree(170, dir)
+
+	if err := r.millRunOnce(); err != nil {
+		t.Fatalf("millRunOnce: %v", err)
+	}
+
+	files, err := r.sink.List(context.Background())
+	if err != nil {
+		t.Fatalf("sink.List: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d surviving backups, want 2: %+v", len(files), files)
+	}
+	for _, f := range files {
+		if f.RotatedAt.Equal(oldest) {
+			t.Fatalf("oldest backup %s should have been removed", f.Name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package roller
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// rotateState is the contents of the <name>.rotate-state sidecar file, used
+// under MultiProcess so concurrent writers can detect a rotation performed
+// by a peer. Inode identifies the active file a rotation produced; Epoch is
+// a monotonically increasing counter so stale state can be told apart from a
+// yet-unseen rotation even when Inode is unavailable (see fileID).
+type rotateState struct {
+	Inode uint64 `json:"inode"`
+	Size  int64  `json:"size"`
+	Epoch int64  `json:"epoch"`
+}
+
+// lockPath and statePath are the sidecar files MultiProcess coordinates
+// through, alongside the active FileName.
+func (r *LumberjackRoller) lockPath() string {
+	return r.newFilename() + ".lock"
+}
+
+func (r *LumberjackRoller) statePath() string {
+	return r.newFilename() + ".rotate-state"
+}
+
+// openLock opens (creating if necessary) the sidecar lock file used to
+// coordinate rotation and appends across processes. It is kept open for the
+// lifetime of the Roller.
+func (r *LumberjackRoller) openLock() error {
+	f, err := os.OpenFile(r.lockPath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("can't open lock file: %w", err)
+	}
+	r.lockFile = f
+	return nil
+}
+
+// withLock runs fn while holding the sidecar lock, exclusively for rotation
+// or shared for appends.
+func (r *LumberjackRoller) withLock(exclusive bool, fn func() error) error {
+	if r.lockFile == nil {
+		if err := r.openLock(); err != nil {
+			return err
+		}
+	}
+	if err := flock(r.lockFile, exclusive); err != nil {
+		return fmt.Errorf("can't lock %s: %w", r.lockPath(), err)
+	}
+	defer funlock(r.lockFile)
+	return fn()
+}
+
+// recordActiveFile remembers the identity of the file r.file now points at,
+// so a later Write can tell whether a peer has since rotated it away.
+func (r *LumberjackRoller) recordActiveFile() {
+	info, err := r.file.Stat()
+	if err != nil {
+		return
+	}
+	r.activeInode, r.haveInode = fileID(info)
+	if state, ok := r.readRotateState(); ok {
+		r.lastEpoch = state.Epoch
+	}
+}
+
+// writeRotateState persists the active file's identity so peers can notice
+// this process just rotated.
+func (r *LumberjackRoller) writeRotateState() error {
+	// Continue from the epoch last persisted by any process, not just this
+	// one, so the counter keeps increasing across peers.
+	if prev, ok := r.readRotateState(); ok && prev.Epoch > r.rotateEpoch {
+		r.rotateEpoch = prev.Epoch
+	}
+	r.rotateEpoch++
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	inode, _ := fileID(info)
+	state := rotateState{Inode: inode, Size: info.Size(), Epoch: r.rotateEpoch}
+	body, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp := r.statePath() + ".tmp"
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.statePath()); err != nil {
+		return err
+	}
+	r.lastEpoch = state.Epoch
+	return nil
+}
+
+// readRotateState loads the sidecar rotation-state file, if any.
+func (r *LumberjackRoller) readRotateState() (rotateState, bool) {
+	body, err := os.ReadFile(r.statePath())
+	if err != nil {
+		return rotateState{}, false
+	}
+	var state rotateState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return rotateState{}, false
+	}
+	return state, true
+}
+
+// syncWithPeers checks whether a peer process has rotated the active file
+// out from under us since we last opened or wrote to it. If so, it reopens
+// the current active file instead of writing to, or racing to rotate, our
+// now-stale descriptor.
+func (r *LumberjackRoller) syncWithPeers() error {
+	state, ok := r.readRotateState()
+	if !ok {
+		return nil
+	}
+
+	rotated := state.Epoch != r.lastEpoch
+	if r.haveInode {
+		rotated = state.Inode != r.activeInode
+	}
+	if !rotated {
+		if info, err := r.file.Stat(); err == nil {
+			r.size = info.Size()
+		}
+		return nil
+	}
+
+	// The old file is about to be closed out from under us; anything still
+	// sitting in the write buffer belongs to it, not whatever we're about
+	// to reopen, so it must land before we close.
+	if err := r.flushLocked(); err != nil {
+		return err
+	}
+	if err := r.close(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.newFilename(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return r.openNew()
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return r.openNew()
+	}
+	r.file = file
+	r.size = info.Size()
+	r.createTime = info.ModTime()
+	r.recordActiveFile()
+	return nil
+}
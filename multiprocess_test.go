@@ -0,0 +1,219 @@
+package roller
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMultiProcessPeerDetectsRotation simulates two processes as two
+// in-process LumberjackRoller instances over the same FileName, so the
+// rotation-detection logic itself can be exercised deterministically without
+// the timing noise of real processes (see TestMultiProcessRealChildProcesses
+// for the real-process variant): one rotates while the other still holds the
+// old file open, and the peer must notice on its next Write and reopen the
+// new active file instead of appending to the now-renamed one.
+func TestMultiProcessPeerDetectsRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+
+	optsA := NewOptions(Filename(filename), Size(1<<20), MultiProcess(true))
+	instA, err := NewLumberjackRollerFromOptions(optsA)
+	if err != nil {
+		t.Fatalf("peer A: %v", err)
+	}
+	a := instA.(*LumberjackRoller)
+	defer a.Close()
+
+	optsB := NewOptions(Filename(filename), Size(1<<20), MultiProcess(true))
+	instB, err := NewLumberjackRollerFromOptions(optsB)
+	if err != nil {
+		t.Fatalf("peer B: %v", err)
+	}
+	b := instB.(*LumberjackRoller)
+	defer b.Close()
+
+	if _, err := b.Write([]byte("before-rotation\n")); err != nil {
+		t.Fatalf("B write before rotation: %v", err)
+	}
+
+	// A rotates; B still holds a descriptor to the now-renamed file.
+	if err := a.Rotate(); err != nil {
+		t.Fatalf("A rotate: %v", err)
+	}
+
+	// B's next Write must detect A's rotation via the sidecar rotate-state
+	// file and reopen the new active file rather than appending to the
+	// stale, renamed one.
+	if _, err := b.Write([]byte("after-rotation\n")); err != nil {
+		t.Fatalf("B write after rotation: %v", err)
+	}
+
+	active, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read active file: %v", err)
+	}
+	if got, want := string(active), "after-rotation\n"; got != want {
+		t.Fatalf("active file = %q, want %q", got, want)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var sawBackup bool
+	for _, e := range entries {
+		name := e.Name()
+		if name == filepath.Base(filename) || strings.HasSuffix(name, ".lock") || strings.HasSuffix(name, ".rotate-state") {
+			continue
+		}
+		sawBackup = true
+		body, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("read backup: %v", err)
+		}
+		if got, want := string(body), "before-rotation\n"; got != want {
+			t.Fatalf("backup %s = %q, want %q", e.Name(), got, want)
+		}
+	}
+	if !sawBackup {
+		t.Fatal("expected a rotated backup file, found none")
+	}
+}
+
+// TestMultiProcessRealChildProcesses spawns two real child processes (via
+// the standard Go helper-process pattern, see TestHelperProcess) that both
+// write to the same FileName under MultiProcess, forcing several rotations
+// while they race. Every line either process wrote must survive exactly
+// once, across the active file and every backup, with no torn or dropped
+// lines from the two descriptors racing at the OS level.
+func TestMultiProcessRealChildProcesses(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		t.Skip("re-exec'd as a helper process")
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	const linesPerProc = 40
+
+	run := func(role string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", filename, role, strconv.Itoa(linesPerProc))
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+		cmd.Stderr = os.Stderr
+		return cmd
+	}
+
+	a := run("A")
+	b := run("B")
+	if err := a.Start(); err != nil {
+		t.Fatalf("start peer A: %v", err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatalf("start peer B: %v", err)
+	}
+	if err := a.Wait(); err != nil {
+		t.Fatalf("peer A: %v", err)
+	}
+	if err := b.Wait(); err != nil {
+		t.Fatalf("peer B: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+
+	want := map[string]int{}
+	for _, role := range []string{"A", "B"} {
+		for i := 0; i < linesPerProc; i++ {
+			want[fmt.Sprintf("%s-%04d", role, i)]++
+		}
+	}
+
+	got := map[string]int{}
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasSuffix(name, ".lock") || strings.HasSuffix(name, ".rotate-state") {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			got[line]++
+		}
+	}
+
+	for line, n := range want {
+		if got[line] != n {
+			t.Errorf("line %q appears %d times across active file + backups, want %d", line, got[line], n)
+		}
+	}
+	for line, n := range got {
+		if want[line] == 0 {
+			t.Errorf("unexpected line %q (x%d) not written by either child", line, n)
+		}
+	}
+}
+
+// TestHelperProcess is not a real test; it is re-exec'd by
+// TestMultiProcessRealChildProcesses as a child process, identified by the
+// GO_WANT_HELPER_PROCESS environment variable, following the standard
+// os/exec helper-process pattern.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for len(args) > 0 {
+		if args[0] == "--" {
+			args = args[1:]
+			break
+		}
+		args = args[1:]
+	}
+	if len(args) != 3 {
+		fmt.Fprintf(os.Stderr, "helper process: want 3 args, got %d: %v\n", len(args), args)
+		os.Exit(2)
+	}
+	filename, role, numStr := args[0], args[1], args[2]
+	n, err := strconv.Atoi(numStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "helper process: bad line count %q: %v\n", numStr, err)
+		os.Exit(2)
+	}
+
+	inst, err := NewLumberjackRollerFromOptions(NewOptions(Filename(filename), Size(200), MultiProcess(true)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "helper process: new roller: %v\n", err)
+		os.Exit(2)
+	}
+	r := inst.(*LumberjackRoller)
+
+	for i := 0; i < n; i++ {
+		line := fmt.Sprintf("%s-%04d\n", role, i)
+		if _, err := r.Write([]byte(line)); err != nil {
+			fmt.Fprintf(os.Stderr, "helper process: write: %v\n", err)
+			os.Exit(2)
+		}
+		// Spread writes out so two rotations landing in the same
+		// millisecond can't collide on backupName and overwrite one
+		// another; BackupTimeFormat only has millisecond resolution.
+		time.Sleep(time.Millisecond)
+	}
+	if err := r.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "helper process: close: %v\n", err)
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
@@ -1,91 +1,245 @@
 package roller
 
 import (
-	"fmt"
-	"path"
-	"path/filepath"
-	"strings"
 	"time"
 )
 
+// RotateStrategy selects when a Write triggers rotation.
+type RotateStrategy int
+
+const (
+	// SizeRotateStrategy rotates once the active file would exceed
+	// FileMaxSize. This is the default.
+	SizeRotateStrategy RotateStrategy = iota
+	// DirectRotateStrategy rotates after every Write, regardless of size.
+	DirectRotateStrategy
+	// TimeRotateStrategy rotates on a schedule, set via Schedule, rather
+	// than in response to a Write.
+	TimeRotateStrategy
+)
+
+// Schedule configures TimeRotateStrategy. Exactly one of Duration or Cron
+// should be set: Duration rotates every fixed interval, Cron evaluates a
+// standard five-field cron expression (minute hour dom month dow) and
+// rotates at the next matching minute.
+type Schedule struct {
+	Duration time.Duration
+	Cron     string
+}
+
+// RotateNameFunc builds the name of the next backup file from the active
+// filename. It is invoked at rotation time, so implementations that embed a
+// timestamp should read the current time themselves.
+type RotateNameFunc func(filename string) string
+
 // Options represents optional behavior you can specify for a new Roller.
 type Options struct {
-	Filename          string
-	Size              int64
-	Duration          time.Duration
-	RotateName        RotateNameFunc
-	LifecycleGlob     string
-	LifecycleSize     int64
-	LifecycleCount    int64
-	LifecycleDuration time.Duration
+	FileName           string
+	RotateStrategy     RotateStrategy
+	FileMaxSize        int64
+	Duration           time.Duration
+	Schedule           Schedule
+	BackupTimeFormat   string
+	BackupTimeLocation *time.Location
+	RotateName         RotateNameFunc
+
+	MaxSize      int64
+	FileMaxCount int
+	FileMaxAge   time.Duration
+	ReservedSize int64
+	MultiProcess bool
+
+	Compress      bool
+	Compression   string
+	CompressLevel int
+
+	Sink BackupSink
+
+	BufferSize    int
+	FlushInterval time.Duration
 }
 
 type Option func(*Options)
 
-type RotateNameFunc func(string) string
-
-func newOptions(opt ...Option) *Options {
-	options := &Options{
-		RotateName: defaultRotateName,
+// NewOptions builds an Options from the given functional options, applying
+// the package defaults first.
+func NewOptions(opts ...Option) Options {
+	options := Options{
+		RotateStrategy:     SizeRotateStrategy,
+		BackupTimeFormat:   backupTimeFormat,
+		BackupTimeLocation: time.Local,
 	}
-	for _, o := range opt {
-		o(options)
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.RotateName == nil {
+		options.RotateName = func(name string) string {
+			return backupName(name, options.BackupTimeFormat, options.BackupTimeLocation)
+		}
 	}
 	return options
 }
 
+// Filename sets the path of the active log file.
 func Filename(n string) Option {
 	return func(o *Options) {
-		o.Filename = n
+		o.FileName = n
 	}
 }
 
+// Size sets the byte size at which the active file is rotated under
+// SizeRotateStrategy.
 func Size(n int64) Option {
 	return func(o *Options) {
-		o.Size = n
+		o.FileMaxSize = n
 	}
 }
 
+// Duration sets an additional, strategy-independent age at which the active
+// file is rotated even if it hasn't reached FileMaxSize.
 func Duration(d time.Duration) Option {
 	return func(o *Options) {
 		o.Duration = d
 	}
 }
 
+// WithRotateStrategy selects when a Write triggers rotation.
+func WithRotateStrategy(s RotateStrategy) Option {
+	return func(o *Options) {
+		o.RotateStrategy = s
+	}
+}
+
+// ScheduleEvery configures TimeRotateStrategy to rotate every d.
+func ScheduleEvery(d time.Duration) Option {
+	return func(o *Options) {
+		o.Schedule = Schedule{Duration: d}
+	}
+}
+
+// ScheduleCron configures TimeRotateStrategy to rotate at the next minute
+// matching the given five-field cron expression.
+func ScheduleCron(expr string) Option {
+	return func(o *Options) {
+		o.Schedule = Schedule{Cron: expr}
+	}
+}
+
+// BackupTimeFormat sets the time.Time layout used to encode the rotation
+// timestamp into backup filenames.
+func BackupTimeFormat(layout string) Option {
+	return func(o *Options) {
+		o.BackupTimeFormat = layout
+	}
+}
+
+// BackupTimeLocation sets the time.Location used when formatting the
+// rotation timestamp in backup filenames.
+func BackupTimeLocation(l *time.Location) Option {
+	return func(o *Options) {
+		o.BackupTimeLocation = l
+	}
+}
+
+// RotateName overrides how backup filenames are built. The default inserts
+// the rotation time, formatted with BackupTimeFormat, between the active
+// filename and its extension.
 func RotateName(fn RotateNameFunc) Option {
 	return func(o *Options) {
 		o.RotateName = fn
 	}
 }
 
-func LifecycleGlob(glob string) Option {
+// MaxSize caps the total size, in bytes, of all retained backups. Once
+// exceeded, the oldest backups are removed first.
+func MaxSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxSize = n
+	}
+}
+
+// FileMaxCount caps the number of retained backups.
+func FileMaxCount(n int) Option {
 	return func(o *Options) {
-		o.LifecycleGlob = glob
+		o.FileMaxCount = n
 	}
 }
 
-func LifecycleSize(s int64) Option {
+// FileMaxAge caps how long a backup is retained, measured from its rotation
+// time.
+func FileMaxAge(d time.Duration) Option {
 	return func(o *Options) {
-		o.LifecycleSize = s
+		o.FileMaxAge = d
 	}
 }
 
-func LifecycleCount(c int64) Option {
+// ReservedSize sets the minimum free space, in bytes, that must remain on
+// the filesystem holding the log directory. Once MaxSize, FileMaxCount, and
+// FileMaxAge have been applied, the mill pass removes the oldest remaining
+// backups until the reserve is met or only the active file is left. A Write
+// that would push free space under the reserve also triggers a mill pass
+// proactively, instead of waiting for the next rotation.
+func ReservedSize(bytes int64) Option {
 	return func(o *Options) {
-		o.LifecycleCount = c
+		o.ReservedSize = bytes
 	}
 }
 
-func LifecycleDuration(d time.Duration) Option {
+// MultiProcess enables coordination, via an advisory file lock and a small
+// on-disk rotation-state file, between several processes writing to the same
+// FileName. Without it, two processes rotating concurrently can corrupt each
+// other's rename.
+func MultiProcess(enabled bool) Option {
 	return func(o *Options) {
-		o.LifecycleDuration = d
+		o.MultiProcess = enabled
 	}
 }
 
-func defaultRotateName(filename string) string {
-	dir := filepath.Dir(filename)
-	ext := filepath.Ext(filename)
-	name := strings.TrimSuffix(filepath.Base(filename), ext)
+// Compress enables gzip compression of backups. Equivalent to
+// Compression("gzip").
+func Compress(b bool) Option {
+	return func(o *Options) {
+		o.Compress = b
+	}
+}
 
-	return path.Join(dir, fmt.Sprintf("%s_%s%s", name, time.Now().Format("2006-01-02T15:04:05.999Z07:00"), ext))
+// Compression selects the codec used to compress backups: "gzip", "zstd",
+// "lz4", or "" to disable compression.
+func Compression(name string) Option {
+	return func(o *Options) {
+		o.Compression = name
+	}
 }
+
+// CompressLevel sets the compression level passed to the selected codec. Its
+// meaning is codec-specific; 0 means "use the codec's default".
+func CompressLevel(n int) Option {
+	return func(o *Options) {
+		o.CompressLevel = n
+	}
+}
+
+// Sink overrides where rotated backups are stored. The default, used when
+// Sink is nil, is a LocalFSSink writing into the active file's directory,
+// compressing with the codec selected via Compression if any.
+func Sink(sink BackupSink) Option {
+	return func(o *Options) {
+		o.Sink = sink
+	}
+}
+
+// BufferedWrite coalesces writes from many goroutines into an internal
+// buffer, flushed to the active file once it reaches size bytes, every
+// flush interval (if positive), or immediately before a rotation. This
+// trades a small window of buffered, not-yet-durable log data for
+// substantially less per-Write lock and syscall overhead under concurrent
+// logging. flush of 0 disables the periodic flush; the buffer still flushes
+// once it reaches size, and always flushes before a rotation.
+func BufferedWrite(size int, flush time.Duration) Option {
+	return func(o *Options) {
+		o.BufferSize = size
+		o.FlushInterval = flush
+	}
+}
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
@@ -1,206 +1,56 @@
 package roller
 
 import (
-	"errors"
-	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"sync"
+	"context"
+	"io"
 	"time"
-
-	"github.com/haormj/fileshredder"
 )
 
-// ErrWriteTooLong indicates that a single write that is longer than the max
-// size allowed in a single file.
-var ErrWriteTooLong = errors.New("write exceeds max file length")
-
-type Roller struct {
-	lock         sync.Mutex
-	options      *Options
-	f            *os.File
-	size         int64
-	createTime   time.Time
-	millCh       chan bool
-	fileShredder *fileshredder.FileShredder
-}
-
-func NewRoller(opts ...Option) (*Roller, error) {
-	options := newOptions(opts...)
-	if len(options.Filename) == 0 {
-		return nil, errors.New("filename can not empty")
-	}
-
-	r := &Roller{
-		options: options,
-		millCh:  make(chan bool),
-	}
-
-	if len(options.LifecycleGlob) > 0 &&
-		(options.LifecycleSize != 0 || options.LifecycleCount != 0 || options.LifecycleDuration != 0) {
-		fs, err := fileshredder.NewFileShredder(
-			fileshredder.GlobPath(options.LifecycleGlob),
-			fileshredder.MaxSize(options.LifecycleSize),
-			fileshredder.MaxAge(options.LifecycleDuration),
-			fileshredder.MaxCount(options.LifecycleCount),
-		)
-		if err != nil {
-			return nil, err
-		}
-		r.fileShredder = fs
-		go r.millRun()
-	}
-
-	if err := r.open(); err != nil {
-		return nil, err
-	}
-
-	return r, nil
-}
-
-func (r *Roller) Write(p []byte) (n int, err error) {
-	writeLen := int64(len(p))
-	if r.options.Size > 0 && writeLen > r.options.Size {
-		return 0, fmt.Errorf("write length %d, max size %d: %w", writeLen, r.options.Size, ErrWriteTooLong)
-	}
-
-	r.lock.Lock()
-	defer r.lock.Unlock()
-
-	if r.isRotate(writeLen) {
-		if err = r.rotate(); err != nil {
-			return
-		}
-	}
-
-	n, err = r.f.Write(p)
-	r.size += int64(n)
-
-	return
-}
-
-func (r *Roller) Close() error {
-	r.lock.Lock()
-	defer r.lock.Unlock()
-
-	close(r.millCh)
-
-	return r.close()
-}
-
-func (r *Roller) Rotate() error {
-	r.lock.Lock()
-	defer r.lock.Unlock()
-
-	return r.rotate()
-}
-
-func (r *Roller) open() error {
-	var f *os.File
-	var err error
-	_, err = os.Stat(r.options.Filename)
-	switch {
-	case err == nil:
-		f, err = r.openExist()
-	case os.IsNotExist(err):
-		f, err = r.openNew()
-	default:
-		return err
-	}
-
-	if err != nil {
-		return err
-	}
-
-	info, err := f.Stat()
-	if err != nil {
-		return err
-	}
-
-	r.f = f
-	r.size = info.Size()
-	r.createTime = info.ModTime()
-
-	return nil
-}
-
-func (r *Roller) openNew() (*os.File, error) {
-	if err := os.MkdirAll(filepath.Dir(r.options.Filename), 0755); err != nil {
-		return nil, err
-	}
-
-	f, err := os.OpenFile(r.options.Filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return nil, err
-	}
-
-	return f, nil
-}
-
-func (r *Roller) openExist() (*os.File, error) {
-	return os.OpenFile(r.options.Filename, os.O_APPEND|os.O_WRONLY, 0644)
-}
-
-func (r *Roller) close() error {
-	if r.f == nil {
-		return nil
-	}
-
-	defer func() {
-		r.f = nil
-		r.size = 0
-		r.createTime = time.Time{}
-	}()
-
-	return r.f.Close()
-}
-
-func (r *Roller) rotate() error {
-	if err := r.close(); err != nil {
-		return err
-	}
-
-	rotateName := r.options.RotateName(r.options.Filename)
-	if err := os.MkdirAll(filepath.Dir(rotateName), 0755); err != nil {
-		return err
-	}
-	if err := os.Rename(r.options.Filename, rotateName); err != nil {
-		return err
-	}
-
-	if err := r.open(); err != nil {
-		return err
-	}
-
-	r.mill()
-
-	return nil
-}
-
-func (r *Roller) isRotate(writeLen int64) bool {
-	if r.options.Size > 0 && writeLen+r.size > r.options.Size {
-		return true
-	}
-
-	if r.options.Duration > 0 && time.Since(r.createTime) > r.options.Duration {
-		return true
-	}
-
-	return false
-}
-
-func (r *Roller) mill() {
-	select {
-	case r.millCh <- true:
-	default:
-	}
-}
-
-func (r *Roller) millRun() {
-	for range r.millCh {
-		if err := r.fileShredder.MillRunOnce(); err != nil {
-			log.Println("MillRunOnce err", err)
-		}
-	}
+// Roller is the common interface implemented by every rotation strategy in
+// this package. It wraps an io.WriteCloser so it can be used as a drop-in
+// destination for log.Logger, zap, or anything else that writes lines, while
+// also exposing the rotation and backup-inspection hooks callers need.
+type Roller interface {
+	io.Writer
+	io.Closer
+
+	// Rotate closes the active file, moves it aside as a backup, and opens a
+	// new active file, regardless of whether the configured rotation
+	// strategy would have triggered one. Useful for wiring up SIGHUP.
+	Rotate() error
+
+	// Backups lists every backup currently held by the configured
+	// BackupSink. The Name of each returned Metadata is the only name
+	// OpenBackup accepts; it is not necessarily the backup's on-disk
+	// filename (a Compressor-suffixed name, for LocalFSSink).
+	Backups(ctx context.Context) ([]Metadata, error)
+
+	// OpenBackup opens a backup previously produced by this Roller, given
+	// the backup's name as returned by Backups. If the backup was written
+	// with a compressor, OpenBackup transparently decompresses it. It
+	// returns an error if name does not match any backup currently reported
+	// by Backups.
+	OpenBackup(name string) (io.ReadCloser, Metadata, error)
+
+	// WriteEntry formats level, ts, msg, and fields into a single buffered
+	// write, avoiding the several small writes a naive structured logger
+	// would otherwise issue per field. See BufferedWrite to also coalesce
+	// entries from many goroutines into fewer underlying writes.
+	WriteEntry(level, ts, msg []byte, fields ...Field) (int, error)
+}
+
+// Metadata describes a single rotated backup file. Compressors that support
+// an embedded header (gzip's comment field, or the framing this package adds
+// for zstd/lz4) persist this alongside the compressed bytes so OpenBackup and
+// a BackupSink's List can answer questions about a backup without
+// re-parsing its filename or decompressing it first.
+type Metadata struct {
+	// Name is the backup's original, uncompressed filename.
+	Name string `json:"name"`
+	// RotatedAt is the time the backup was produced.
+	RotatedAt time.Time `json:"rotated_at"`
+	// UncompressedSize is the size, in bytes, of the original content.
+	UncompressedSize int64 `json:"uncompressed_size"`
+	// SHA256 is the hex-encoded sha256 checksum of the original content.
+	SHA256 string `json:"sha256"`
 }
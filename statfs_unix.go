@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package roller
+
+import "golang.org/x/sys/unix"
+
+// diskFree returns the number of bytes available to an unprivileged user on
+// the filesystem holding dir.
+func diskFree(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package roller
+
+import "golang.org/x/sys/windows"
+
+// diskFree returns the number of bytes available to the current user on the
+// volume holding dir.
+func diskFree(dir string) (uint64, error) {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var freeAvailable, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(path, &freeAvailable, &total, &totalFree); err != nil {
+		return 0, err
+	}
+	return freeAvailable, nil
+}